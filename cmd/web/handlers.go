@@ -1,10 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"html/template"
 	"net/http"
 	"strconv"
+	"strings"
+
+	"davappler/snippetbox/pkg/models"
 )
 
 
@@ -15,12 +20,20 @@ func (app *application) home(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	ctx, cancel := app.queryContext(r)
+	defer cancel()
+
+	snippets, err := app.snippets.Latest(ctx)
+	if err != nil {
+		app.storeError(w, err)
+		return
+	}
 
-	// Initialize a slice containing the paths to the two files. Note that the 
+	// Initialize a slice containing the paths to the three files. Note that the
 	// home.page.tmpl file must be the *first* file in the slice.
 	files := []string{
 		"./ui/html/home.page.tmpl",
-		"./ui/html/base.layout.tmpl", 
+		"./ui/html/base.layout.tmpl",
 		"./ui/html/footer.partial.tmpl",
 	}
 
@@ -35,18 +48,150 @@ func (app *application) home(w http.ResponseWriter, r *http.Request) {
 
 
 
-	// We then use the Execute() method on the template set to write the template 
-	// content as the response body. The last parameter to Execute() represents any
-	// dynamic data that we want to pass in, which for now we'll leave as nil.
+	// We then use the Execute() method on the template set to write the template
+	// content as the response body, passing the latest snippets as the dynamic data.
 
-	err = ts.Execute(w, nil)
+	err = ts.Execute(w, &snippetListData{Snippets: snippets})
 	if err != nil {
 		app.errorLog.Println(err.Error())
-		app.serverError(w, err) 
+		app.serverError(w, err)
 	}
 }
 
 
+// snippetListData is the data passed to the snippets.page.tmpl template.
+type snippetListData struct {
+	Snippets []*models.Snippet
+	PageInfo models.PageInfo
+	Query    string
+}
+
+// queryContext derives a context from the request, bounded by
+// app.dbQueryTimeout, so a slow query is aborted at the DB layer instead
+// of tying up a connection past the point the client is still waiting.
+func (app *application) queryContext(r *http.Request) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(r.Context(), app.dbQueryTimeout)
+}
+
+// storeError maps an error returned from a SnippetStore call to the
+// appropriate HTTP response. A context cancelled by a client disconnect,
+// or one that exceeded its deadline, gets a 499/504-style response
+// instead of a generic 500.
+func (app *application) storeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, models.ErrNoRecord):
+		app.notFound(w)
+	case errors.Is(err, models.ErrInvalidCursor):
+		app.clientError(w, http.StatusBadRequest)
+	case errors.Is(err, context.DeadlineExceeded):
+		app.clientError(w, http.StatusGatewayTimeout)
+	case errors.Is(err, context.Canceled):
+		app.clientError(w, 499) // non-standard "Client Closed Request"
+	default:
+		app.serverError(w, err)
+	}
+}
+
+// snippetList renders a page of the most recent snippets, honouring the
+// ?limit=, ?cursor= and ?q= query string parameters.
+func (app *application) snippetList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil || limit <= 0 {
+		limit = 10
+	}
+
+	opts := models.ListOptions{
+		Limit:  limit,
+		Cursor: query.Get("cursor"),
+		Query:  query.Get("q"),
+	}
+
+	ctx, cancel := app.queryContext(r)
+	defer cancel()
+
+	snippets, page, err := app.snippets.List(ctx, opts)
+	if err != nil {
+		app.storeError(w, err)
+		return
+	}
+
+	files := []string{
+		"./ui/html/snippets.page.tmpl",
+		"./ui/html/base.layout.tmpl",
+		"./ui/html/footer.partial.tmpl",
+	}
+
+	ts, err := template.ParseFiles(files...)
+	if err != nil {
+		app.errorLog.Println(err.Error())
+		app.serverError(w, err)
+		return
+	}
+
+	err = ts.Execute(w, &snippetListData{
+		Snippets: snippets,
+		PageInfo: page,
+		Query:    opts.Query,
+	})
+	if err != nil {
+		app.errorLog.Println(err.Error())
+		app.serverError(w, err)
+	}
+}
+
+// tagSnippetStore is implemented by storage backends that support the
+// tags feature. Backends that don't (e.g. postgres, sqlite) are reported
+// as not implemented rather than causing a panic.
+type tagSnippetStore interface {
+	ListByTag(ctx context.Context, name string) ([]*models.Snippet, error)
+}
+
+// snippetsByTag renders the snippets associated with the tag named in the
+// /tag/<name> path.
+func (app *application) snippetsByTag(w http.ResponseWriter, r *http.Request) {
+	name := strings.TrimPrefix(r.URL.Path, "/tag/")
+	if name == "" {
+		app.notFound(w)
+		return
+	}
+
+	store, ok := app.snippets.(tagSnippetStore)
+	if !ok {
+		app.clientError(w, http.StatusNotImplemented)
+		return
+	}
+
+	ctx, cancel := app.queryContext(r)
+	defer cancel()
+
+	snippets, err := store.ListByTag(ctx, name)
+	if err != nil {
+		app.storeError(w, err)
+		return
+	}
+
+	files := []string{
+		"./ui/html/snippets.page.tmpl",
+		"./ui/html/base.layout.tmpl",
+		"./ui/html/footer.partial.tmpl",
+	}
+
+	ts, err := template.ParseFiles(files...)
+	if err != nil {
+		app.errorLog.Println(err.Error())
+		app.serverError(w, err)
+		return
+	}
+
+	err = ts.Execute(w, &snippetListData{Snippets: snippets, Query: name})
+	if err != nil {
+		app.errorLog.Println(err.Error())
+		app.serverError(w, err)
+	}
+}
+
 func (app *application) showSnippet(w http.ResponseWriter, r *http.Request) {
 	
 	id, err := strconv.Atoi(r.URL.Query().Get("id"))
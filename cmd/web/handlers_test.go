@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"davappler/snippetbox/pkg/models"
+)
+
+func TestSnippetsByTag(t *testing.T) {
+	app := newTestApplication(t)
+	ts := newTestServer(t, app.routes())
+	defer ts.Close()
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+	}{
+		{"missing tag name", "/tag/", http.StatusNotFound},
+		// mock.SnippetModel doesn't implement tagSnippetStore, so this
+		// exercises the graceful-degradation path rather than a panic.
+		{"unsupported backend", "/tag/gardening", http.StatusNotImplemented},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.get(t, tt.urlPath)
+			if code != tt.wantCode {
+				t.Errorf("want %d; got %d", tt.wantCode, code)
+			}
+		})
+	}
+}
+
+func TestStoreError(t *testing.T) {
+	app := newTestApplication(t)
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+	}{
+		{"no record", models.ErrNoRecord, http.StatusNotFound},
+		{"invalid cursor", fmt.Errorf("wrap: %w", models.ErrInvalidCursor), http.StatusBadRequest},
+		{"deadline exceeded", context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{"canceled", context.Canceled, 499},
+		{"other", errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			app.storeError(rr, tt.err)
+
+			if rr.Code != tt.wantCode {
+				t.Errorf("want %d; got %d", tt.wantCode, rr.Code)
+			}
+		})
+	}
+}
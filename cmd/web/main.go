@@ -1,94 +1,256 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"flag"
+	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
+	"davappler/snippetbox/pkg/models"
+	"davappler/snippetbox/pkg/models/migrations"
 	"davappler/snippetbox/pkg/models/mysql"
+	"davappler/snippetbox/pkg/models/postgres"
+	"davappler/snippetbox/pkg/models/sqlite"
 
-	_ "github.com/go-sql-driver/mysql"
+	mysqldriver "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
 // Define an application struct to hold the application-wide dependencies for the
 // web application. For now we'll only include fields for the two custom loggers, but // we'll add more to it as the build progresses.
 type application struct {
 	errorLog *log.Logger
-	infoLog *log.Logger 
-	snippets *mysql.SnippetModel
+	infoLog *log.Logger
+	snippets models.SnippetStore
+	dbQueryTimeout time.Duration
 }
 
 func main() {
 
 
 
-	// Define a new command-line flag with the name 'addr', a default value of ":4000" 
+	// Define a new command-line flag with the name 'addr', a default value of ":4000"
 	// and some short help text explaining what the flag controls. The value of the
 	// flag will be stored in the addr variable at runtime.
 	addr := flag.String("addr", ":4000", "HTTP network address")
 
-	// Define a new command-line flag for the MySQL DSN string.
-	dsn := flag.String("dsn", "web:pass@/snippetbox?parseTime=true", "MySQL data source name")
+	// Define a new command-line flag for the DSN string.
+	dsn := flag.String("dsn", "web:pass@/snippetbox?parseTime=true", "Data source name")
+
+	// Define a new command-line flag to select which storage backend to use.
+	driver := flag.String("driver", "mysql", "Database driver to use (mysql, postgres or sqlite)")
+
+	// Define flags for running schema migrations. The embedded migrations
+	// are written in MySQL's dialect (AUTO_INCREMENT, FULLTEXT indexes),
+	// so both flags are rejected for -driver postgres/sqlite; those
+	// backends need their schema created by other means.
+	migrate := flag.String("migrate", "", "Run schema migrations (up, down or status), then exit without starting the server (mysql driver only)")
+	migrateSteps := flag.Int("migrate-steps", 0, "Limit -migrate up/down to N migrations (0 means all)")
+	autoMigrate := flag.Bool("auto-migrate", false, "Apply any pending migrations before starting the server (mysql driver only)")
+
+	// Define flags for tuning the database connection pool.
+	dbMaxOpen := flag.Int("db-max-open", 25, "Maximum number of open DB connections")
+	dbMaxIdle := flag.Int("db-max-idle", 25, "Maximum number of idle DB connections")
+	dbConnMaxLifetime := flag.Duration("db-conn-max-lifetime", 5*time.Minute, "Maximum amount of time a DB connection may be reused")
+
+	// dbQueryTimeout bounds each database query derived from a request's
+	// context, so a slow query can't hold a connection open indefinitely.
+	dbQueryTimeout := flag.Duration("db-query-timeout", 3*time.Second, "Timeout applied to each database query")
 
 	// Importantly, we use the flag.Parse() function to parse the command-line flag.
 	// This reads in the command-line flag value and assigns it to the addr
 	// variable. You need to call this *before* you use the addr variable
-	// otherwise it will always contain the default value of ":4000". If any errors are 
+	// otherwise it will always contain the default value of ":4000". If any errors are
 	// encountered during parsing the application will be terminated.
 	flag.Parse()
 
 
 
-	// Use log.New() to create a logger for writing information messages. This takes 
-	// three parameters: the destination to write the logs to (os.Stdout), a string 
+	// Use log.New() to create a logger for writing information messages. This takes
+	// three parameters: the destination to write the logs to (os.Stdout), a string
 	// prefix for message (INFO followed by a tab), and flags to indicate what
-	// additional information to include (local date and time). Note that the flags 
+	// additional information to include (local date and time). Note that the flags
 	// are joined using the bitwise OR operator |.
 	infoLog := log.New(os.Stdout, "INFO\t", log.Ldate|log.Ltime)
-	// Create a logger for writing error messages in the same way, but use stderr as 
+	// Create a logger for writing error messages in the same way, but use stderr as
 	// the destination and use the log.Lshortfile flag to include the relevant
 	// file name and line number.
 	errorLog := log.New(os.Stderr, "ERROR\t", log.Ldate|log.Ltime|log.Lshortfile)
 
 
-	db, err := openDB(*dsn)
-	if err != nil { 
+	db, err := openDB(*driver, *dsn, *dbMaxOpen, *dbMaxIdle, *dbConnMaxLifetime)
+	if err != nil {
 		errorLog.Fatal(err)
 	}
 
 	defer db.Close()
 
-	app := &application{ 
-		errorLog: errorLog, 
+	if *migrate != "" {
+		if *driver != "mysql" {
+			errorLog.Fatalf("main: -migrate is only supported with -driver mysql (got %q)", *driver)
+		}
+		if err := runMigrateCommand(db, *migrate, *migrateSteps, infoLog); err != nil {
+			errorLog.Fatal(err)
+		}
+		return
+	}
+
+	if *autoMigrate {
+		if *driver != "mysql" {
+			errorLog.Fatalf("main: -auto-migrate is only supported with -driver mysql (got %q)", *driver)
+		}
+		runner, err := migrations.NewRunner(db)
+		if err != nil {
+			errorLog.Fatal(err)
+		}
+
+		applied, err := runner.Up(0)
+		if err != nil {
+			errorLog.Fatal(err)
+		}
+		infoLog.Printf("applied %d pending migration(s)", applied)
+	}
+
+	snippets, err := newSnippetStore(*driver, db)
+	if err != nil {
+		errorLog.Fatal(err)
+	}
+	if c, ok := snippets.(interface{ Close() error }); ok {
+		defer c.Close()
+	}
+
+	app := &application{
+		errorLog: errorLog,
 		infoLog: infoLog,
-		snippets: &mysql.SnippetModel{DB: db},
+		snippets: snippets,
+		dbQueryTimeout: *dbQueryTimeout,
 	}
 
-	srv := &http.Server{ 
+	srv := &http.Server{
 		Addr: *addr,
 		ErrorLog: errorLog,
-		Handler: app.routes(), // Call the new app.routes() method 
+		Handler: app.routes(), // Call the new app.routes() method
 	}
 
 
 	// Write messages using the two new loggers, instead of the standard logger.
-	infoLog.Printf("Starting server on %s", *addr) 
+	infoLog.Printf("Starting server on %s", *addr)
 	errr := srv.ListenAndServe()
 	errorLog.Fatal(errr)
 
 }
 
+// openDB opens a connection pool for the given driver. Where the driver
+// supports it (currently mysql), the DSN is parsed and normalized with
+// sane defaults before the connection is opened. maxOpenConns, maxIdleConns
+// and connMaxLifetime tune the pool once it's open.
+func openDB(driver, dsn string, maxOpenConns, maxIdleConns int, connMaxLifetime time.Duration) (*sql.DB, error) {
+	sqlDriverName := driver
+
+	switch driver {
+	case "mysql":
+		cfg, err := mysqldriver.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("main: invalid mysql dsn: %w", err)
+		}
 
+		cfg.ParseTime = true
+		if cfg.Timeout == 0 {
+			cfg.Timeout = 5 * time.Second
+		}
+		if cfg.ReadTimeout == 0 {
+			cfg.ReadTimeout = 30 * time.Second
+		}
+		if cfg.WriteTimeout == 0 {
+			cfg.WriteTimeout = 30 * time.Second
+		}
 
-func openDB(dsn string) (*sql.DB, error) {
-	db, err := sql.Open("mysql", dsn)
-	if err != nil { return nil, err
+		dsn = cfg.FormatDSN()
+	case "postgres":
+		// lib/pq accepts the DSN as-is.
+	case "sqlite":
+		// The mattn/go-sqlite3 driver is registered under the name
+		// "sqlite3", and accepts the DSN (a file path) as-is.
+		sqlDriverName = "sqlite3"
+	default:
+		return nil, fmt.Errorf("main: unknown driver %q", driver)
 	}
-	if err = db.Ping(); err != nil {
-	return nil, err }
-	return db, nil 
+
+	db, err := sql.Open(sqlDriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+	db.SetConnMaxLifetime(connMaxLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err = db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	return db, nil
 }
 
+// runMigrateCommand runs the -migrate subcommand (up, down or status)
+// against db and logs what it did.
+func runMigrateCommand(db *sql.DB, command string, steps int, infoLog *log.Logger) error {
+	runner, err := migrations.NewRunner(db)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "up":
+		n, err := runner.Up(steps)
+		if err != nil {
+			return err
+		}
+		infoLog.Printf("applied %d migration(s)", n)
+	case "down":
+		n, err := runner.Down(steps)
+		if err != nil {
+			return err
+		}
+		infoLog.Printf("reverted %d migration(s)", n)
+	case "status":
+		all, applied, err := runner.Status()
+		if err != nil {
+			return err
+		}
+		for _, m := range all {
+			state := "pending"
+			if applied[m.Version] {
+				state = "applied"
+			}
+			infoLog.Printf("%04d_%s: %s", m.Version, m.Name, state)
+		}
+	default:
+		return fmt.Errorf("main: unknown -migrate value %q (want up, down or status)", command)
+	}
 
+	return nil
+}
+
+// newSnippetStore returns the models.SnippetStore implementation for the
+// given driver, backed by db.
+func newSnippetStore(driver string, db *sql.DB) (models.SnippetStore, error) {
+	switch driver {
+	case "mysql":
+		return &mysql.SnippetModel{DB: db}, nil
+	case "postgres":
+		return &postgres.SnippetModel{DB: db}, nil
+	case "sqlite":
+		return &sqlite.SnippetModel{DB: db}, nil
+	default:
+		return nil, fmt.Errorf("main: unknown driver %q", driver)
+	}
+}
@@ -0,0 +1,16 @@
+package main
+
+import "net/http"
+
+// routes returns a ServeMux containing all the application's routes.
+func (app *application) routes() *http.ServeMux {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/", app.home)
+	mux.HandleFunc("/snippets", app.snippetList)
+	mux.HandleFunc("/snippet", app.showSnippet)
+	mux.HandleFunc("/snippet/create", app.createSnippet)
+	mux.HandleFunc("/tag/", app.snippetsByTag)
+
+	return mux
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"davappler/snippetbox/pkg/models/mock"
+)
+
+// newTestApplication returns an application wired to the in-memory mock
+// SnippetModel, so handler tests don't need a running database.
+func newTestApplication(t *testing.T) *application {
+	return &application{
+		errorLog:       log.New(io.Discard, "", 0),
+		infoLog:        log.New(io.Discard, "", 0),
+		snippets:       &mock.SnippetModel{},
+		dbQueryTimeout: 3 * time.Second,
+	}
+}
+
+// testServer wraps httptest.Server with a convenience method for GET
+// requests.
+type testServer struct {
+	*httptest.Server
+}
+
+func newTestServer(t *testing.T, h http.Handler) *testServer {
+	return &testServer{httptest.NewServer(h)}
+}
+
+// get issues a GET request against the test server and returns the
+// response status code, headers and body.
+func (ts *testServer) get(t *testing.T, urlPath string) (int, http.Header, string) {
+	rs, err := ts.Client().Get(ts.URL + urlPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rs.Body.Close()
+
+	body, err := io.ReadAll(rs.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rs.StatusCode, rs.Header, string(body)
+}
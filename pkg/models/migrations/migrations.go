@@ -0,0 +1,99 @@
+// Package migrations is a small, dependency-free schema migration runner.
+// Migration files are embedded at build time and applied in version order,
+// with applied versions tracked in a schema_migrations table so runs are
+// idempotent and operators don't need an external migrate binary.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed sql/*.sql
+var sqlFiles embed.FS
+
+// Migration is a single versioned schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Load reads and parses all embedded migration files, sorted by version.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(sqlFiles, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("migrations: %w", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+
+		version, direction, base, err := parseFilename(name)
+		if err != nil {
+			return nil, err
+		}
+
+		contents, err := sqlFiles.ReadFile("sql/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("migrations: reading %s: %w", name, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: base}
+			byVersion[version] = m
+		}
+
+		switch direction {
+		case "up":
+			m.Up = string(contents)
+		case "down":
+			m.Down = string(contents)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
+
+// parseFilename splits a migration filename of the form
+// "0001_create_snippets.up.sql" into its version, direction (up/down) and
+// base name.
+func parseFilename(name string) (version int, direction, base string, err error) {
+	trimmed := strings.TrimSuffix(name, ".sql")
+
+	switch {
+	case strings.HasSuffix(trimmed, ".up"):
+		direction = "up"
+		trimmed = strings.TrimSuffix(trimmed, ".up")
+	case strings.HasSuffix(trimmed, ".down"):
+		direction = "down"
+		trimmed = strings.TrimSuffix(trimmed, ".down")
+	default:
+		return 0, "", "", fmt.Errorf("migrations: %s has no .up/.down suffix", name)
+	}
+
+	parts := strings.SplitN(trimmed, "_", 2)
+	if len(parts) != 2 {
+		return 0, "", "", fmt.Errorf("migrations: %s is not named <version>_<name>", name)
+	}
+
+	version, err = strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", "", fmt.Errorf("migrations: %s has a non-numeric version: %w", name, err)
+	}
+
+	return version, direction, parts[1], nil
+}
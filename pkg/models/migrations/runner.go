@@ -0,0 +1,147 @@
+package migrations
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const createSchemaMigrationsTable = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+	version BIGINT NOT NULL PRIMARY KEY,
+	name VARCHAR(255) NOT NULL,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
+// Runner applies and reverts migrations against DB, tracking progress in
+// the schema_migrations table.
+type Runner struct {
+	DB *sql.DB
+}
+
+// NewRunner returns a Runner for db, creating the schema_migrations
+// bookkeeping table if it doesn't already exist.
+func NewRunner(db *sql.DB) (*Runner, error) {
+	if _, err := db.Exec(createSchemaMigrationsTable); err != nil {
+		return nil, fmt.Errorf("migrations: creating schema_migrations: %w", err)
+	}
+	return &Runner{DB: db}, nil
+}
+
+// Applied returns the set of migration versions already recorded as
+// applied.
+func (r *Runner) Applied() (map[int]bool, error) {
+	rows, err := r.DB.Query("SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+
+	return applied, rows.Err()
+}
+
+// Status reports every known migration alongside whether it's been
+// applied.
+func (r *Runner) Status() ([]Migration, map[int]bool, error) {
+	all, err := Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	applied, err := r.Applied()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return all, applied, nil
+}
+
+// Up applies up to steps pending migrations, oldest first. A steps value
+// <= 0 applies every pending migration.
+func (r *Runner) Up(steps int) (int, error) {
+	all, applied, err := r.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	appliedCount := 0
+	for _, m := range all {
+		if steps > 0 && appliedCount >= steps {
+			break
+		}
+		if applied[m.Version] {
+			continue
+		}
+
+		if err := r.exec(m.Up); err != nil {
+			return appliedCount, fmt.Errorf("migrations: applying %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		if _, err := r.DB.Exec("INSERT INTO schema_migrations (version, name) VALUES (?, ?)", m.Version, m.Name); err != nil {
+			return appliedCount, fmt.Errorf("migrations: recording %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		appliedCount++
+	}
+
+	return appliedCount, nil
+}
+
+// Down reverts up to steps applied migrations, newest first. A steps value
+// <= 0 reverts every applied migration.
+func (r *Runner) Down(steps int) (int, error) {
+	all, applied, err := r.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	reverted := 0
+	for i := len(all) - 1; i >= 0; i-- {
+		if steps > 0 && reverted >= steps {
+			break
+		}
+
+		m := all[i]
+		if !applied[m.Version] {
+			continue
+		}
+
+		if err := r.exec(m.Down); err != nil {
+			return reverted, fmt.Errorf("migrations: reverting %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		if _, err := r.DB.Exec("DELETE FROM schema_migrations WHERE version = ?", m.Version); err != nil {
+			return reverted, fmt.Errorf("migrations: unrecording %04d_%s: %w", m.Version, m.Name, err)
+		}
+
+		reverted++
+	}
+
+	return reverted, nil
+}
+
+// exec runs stmt, which may contain several semicolon-separated
+// statements, against the connection pool. Statements are split and run
+// individually rather than as one multi-statement Exec, since that isn't
+// supported by every driver/DSN combination.
+func (r *Runner) exec(stmt string) error {
+	for _, s := range strings.Split(stmt, ";") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if _, err := r.DB.Exec(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
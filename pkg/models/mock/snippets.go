@@ -0,0 +1,57 @@
+// Package mock provides an in-memory models.SnippetStore implementation
+// for use in handler tests, so they don't need a running database.
+package mock
+
+import (
+	"context"
+	"time"
+
+	"davappler/snippetbox/pkg/models"
+)
+
+var _ models.SnippetStore = (*SnippetModel)(nil)
+
+var mockSnippet = &models.Snippet{
+	ID:      1,
+	Title:   "An old silent pond",
+	Content: "An old silent pond...\nA frog jumps into the pond,\nsplash! Silence again.",
+	Created: time.Now(),
+	Expires: time.Now(),
+}
+
+// SnippetModel is a fake models.SnippetStore backed by fixed, in-memory
+// data.
+type SnippetModel struct{}
+
+// Insert always reports the new snippet as having id 2.
+func (m *SnippetModel) Insert(ctx context.Context, title, content, expires string) (int, error) {
+	return 2, nil
+}
+
+// Get returns mockSnippet for id 1, and models.ErrNoRecord otherwise.
+func (m *SnippetModel) Get(ctx context.Context, id int) (*models.Snippet, error) {
+	if id == 1 {
+		return mockSnippet, nil
+	}
+	return nil, models.ErrNoRecord
+}
+
+// Latest returns a single-element slice containing mockSnippet.
+func (m *SnippetModel) Latest(ctx context.Context) ([]*models.Snippet, error) {
+	return []*models.Snippet{mockSnippet}, nil
+}
+
+// List returns a single-element page containing mockSnippet, ignoring opts.
+func (m *SnippetModel) List(ctx context.Context, opts models.ListOptions) ([]*models.Snippet, models.PageInfo, error) {
+	return []*models.Snippet{mockSnippet}, models.PageInfo{}, nil
+}
+
+// Update is a no-op that always succeeds.
+func (m *SnippetModel) Update(ctx context.Context, id int, title, content string) error {
+	return nil
+}
+
+// Delete is a no-op that always succeeds.
+func (m *SnippetModel) Delete(ctx context.Context, id int) error {
+	return nil
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoRecord is returned from Get when a snippet with the requested id
+// doesn't exist in the database.
+var ErrNoRecord = errors.New("models: no matching record found")
+
+// ErrInvalidCursor is returned from DecodeCursor (and so from List) when a
+// ListOptions.Cursor value is malformed or has been tampered with. The
+// returned error wraps ErrInvalidCursor and carries the underlying parse
+// error's text, so callers should check for it with errors.Is rather than
+// comparing errors directly.
+var ErrInvalidCursor = errors.New("models: invalid cursor")
+
+// Snippet holds the data for an individual snippet.
+type Snippet struct {
+	ID      int
+	Title   string
+	Content string
+	Created time.Time
+	Expires time.Time
+}
+
+// ListOptions controls pagination, search and filtering for a listing
+// query such as SnippetModel.List.
+type ListOptions struct {
+	// Limit caps the number of snippets returned by a single page. A
+	// value <= 0 falls back to the store's default page size.
+	Limit int
+
+	// Cursor is an opaque, base64-encoded pagination token previously
+	// returned as PageInfo.NextCursor. A blank cursor starts from the
+	// most recent snippet.
+	Cursor string
+
+	// Query, when non-empty, restricts the result set to snippets matching
+	// the search term in title or content. The postgres and sqlite
+	// backends guarantee substring containment. The mysql backend matches
+	// against a FULLTEXT index in natural language mode instead, which
+	// ranks by relevance and drops stopwords and short words rather than
+	// guaranteeing containment — callers that switch -driver should not
+	// assume identical result sets for the same Query.
+	Query string
+
+	// IncludeExpired includes snippets whose expiry date has already
+	// passed. By default expired snippets are omitted.
+	IncludeExpired bool
+}
+
+// PageInfo describes a single page of results returned alongside a
+// listing query.
+type PageInfo struct {
+	// NextCursor is passed back as ListOptions.Cursor to fetch the page
+	// that follows this one. It's empty when HasMore is false.
+	NextCursor string
+
+	// HasMore reports whether further results exist beyond this page.
+	HasMore bool
+}
+
+// SnippetStore is the interface implemented by each storage backend
+// (mysql, postgres, sqlite, ...) that supports snippet persistence.
+// Handlers depend on this interface rather than a concrete backend so the
+// storage layer can be swapped, faked in tests, or selected at runtime.
+// Every method takes a context so a client disconnect or slow query can be
+// aborted at the storage layer instead of tying up a connection.
+type SnippetStore interface {
+	Insert(ctx context.Context, title, content, expires string) (int, error)
+	Get(ctx context.Context, id int) (*Snippet, error)
+	Latest(ctx context.Context) ([]*Snippet, error)
+	List(ctx context.Context, opts ListOptions) ([]*Snippet, PageInfo, error)
+	Update(ctx context.Context, id int, title, content string) error
+	Delete(ctx context.Context, id int) error
+}
+
+// EncodeCursor packs a (created, id) keyset position into the opaque
+// pagination token handed back to callers as PageInfo.NextCursor. Shared
+// across backends so cursors stay interchangeable regardless of which
+// SnippetStore implementation produced them.
+func EncodeCursor(created time.Time, id int) string {
+	raw := fmt.Sprintf("%d|%d", created.UnixNano(), id)
+	return base64.URLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor reverses EncodeCursor, returning an error if the cursor has
+// been tampered with or is otherwise malformed.
+func DecodeCursor(cursor string) (time.Time, int, error) {
+	raw, err := base64.URLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	var nanos int64
+	var id int
+	if _, err := fmt.Sscanf(string(raw), "%d|%d", &nanos, &id); err != nil {
+		return time.Time{}, 0, fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+
+	return time.Unix(0, nanos).UTC(), id, nil
+}
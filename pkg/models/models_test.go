@@ -0,0 +1,37 @@
+package models
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeCursorRoundTrip(t *testing.T) {
+	want := time.Now().UTC().Truncate(time.Nanosecond)
+
+	cursor := EncodeCursor(want, 42)
+
+	got, id, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor returned an error: %v", err)
+	}
+	if !got.Equal(want) {
+		t.Errorf("want created %v; got %v", want, got)
+	}
+	if id != 42 {
+		t.Errorf("want id 42; got %d", id)
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	tests := []string{
+		"not-valid-base64!!!",
+		"",
+	}
+
+	for _, cursor := range tests {
+		if _, _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q): want ErrInvalidCursor; got %v", cursor, err)
+		}
+	}
+}
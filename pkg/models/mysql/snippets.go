@@ -1,46 +1,119 @@
 package mysql
 
 import (
+	"context"
 	"database/sql"
+	"strings"
+	"sync"
 
 	"davappler/snippetbox/pkg/models"
 )
 
-// Define a SnippetModel type which wraps a sql.DB connection pool.
-type SnippetModel struct { 
+// defaultListLimit is the page size used by List when the caller doesn't
+// specify one, and the number of snippets returned by Latest.
+const defaultListLimit = 10
+
+// Named keys for the statements cached by SnippetModel.stmt.
+const (
+	stmtInsertSnippet  = "insert_snippet"
+	stmtGetSnippet     = "get_snippet"
+	stmtLatestSnippets = "latest_snippets"
+)
+
+const insertSnippetQuery = `INSERT INTO snippets (title, content, created, expires)
+			  VALUES(?, ?, UTC_TIMESTAMP(), DATE_ADD(UTC_TIMESTAMP(), INTERVAL ? DAY))`
+
+const getSnippetQuery = `SELECT id, title, content, created, expires FROM snippets
+	WHERE expires > UTC_TIMESTAMP() AND id = ?`
+
+const latestSnippetsQuery = `SELECT id, title, content, created, expires FROM snippets
+	WHERE expires > UTC_TIMESTAMP()
+	ORDER BY created DESC LIMIT ?`
+
+// Define a SnippetModel type which wraps a sql.DB connection pool. It
+// lazily prepares and caches the statements it uses most often, so
+// repeated calls to Insert, Get and Latest don't re-prepare the same
+// query against the server every time.
+type SnippetModel struct {
 	DB *sql.DB
+
+	mu    sync.Mutex
+	stmts map[string]*sql.Stmt
 }
-// This will insert a new snippet into the database.
-func (m *SnippetModel) Insert(title, content, expires string) (int, error) { 
 
+// stmt returns the cached, prepared statement for key, preparing it
+// against query the first time it's requested.
+func (m *SnippetModel) stmt(key, query string) (*sql.Stmt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
+	if stmt, ok := m.stmts[key]; ok {
+		return stmt, nil
+	}
 
-	stmt := `INSERT INTO snippets (title, content, created, expires)
-			  VALUES(?, ?, UTC_TIMESTAMP(), DATE_ADD(UTC_TIMESTAMP(), INTERVAL ? DAY))`
+	stmt, err := m.DB.Prepare(query)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.stmts == nil {
+		m.stmts = make(map[string]*sql.Stmt)
+	}
+	m.stmts[key] = stmt
 
+	return stmt, nil
+}
 
-	result, err := m.DB.Exec(stmt, title, content, expires) 
+// Close releases every prepared statement held by the model. It should be
+// called once, during application shutdown.
+func (m *SnippetModel) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var firstErr error
+	for key, stmt := range m.stmts {
+		if err := stmt.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		delete(m.stmts, key)
+	}
+
+	return firstErr
+}
+
+// This will insert a new snippet into the database.
+func (m *SnippetModel) Insert(ctx context.Context, title, content, expires string) (int, error) {
+	stmt, err := m.stmt(stmtInsertSnippet, insertSnippetQuery)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := stmt.ExecContext(ctx, title, content, expires)
 	if err != nil {
-		return 0, err 
+		return 0, err
 	}
 
 	id, err := result.LastInsertId()
 	if err != nil {
-		return 0, err 
+		return 0, err
 	}
 
 	return int(id), nil
 }
+
 // This will return a specific snippet based on its id.
-func (m *SnippetModel) Get(id int) (*models.Snippet, error) { 
+func (m *SnippetModel) Get(ctx context.Context, id int) (*models.Snippet, error) {
+	stmt, err := m.stmt(stmtGetSnippet, getSnippetQuery)
+	if err != nil {
+		return nil, err
+	}
 
-	stmt := `SELECT id, title, content, created, expires FROM snippets
-	WHERE expires > UTC_TIMESTAMP() AND id = ?`
-	// Use the QueryRow() method on the connection pool to execute our
-	// SQL statement, passing in the untrusted id variable as the value for the 
-	// placeholder parameter. This returns a pointer to a sql.Row object which 
-	// holds the result from the database.
-	row := m.DB.QueryRow(stmt, id)
+	// Use QueryRowContext() on the prepared statement to execute our SQL
+	// statement, passing in the untrusted id variable as the value for
+	// the placeholder parameter. This returns a pointer to a sql.Row
+	// object which holds the result from the database. The query is
+	// aborted if ctx is cancelled or its deadline passes.
+	row := stmt.QueryRowContext(ctx, id)
 
 	// Initialize a pointer to a new zeroed Snippet struct.
 	s := &models.Snippet{}
@@ -49,17 +122,136 @@ func (m *SnippetModel) Get(id int) (*models.Snippet, error) {
 	// to row.Scan are *pointers* to the place you want to copy the data into,
 	// and the number of arguments must be exactly the same as the number of
 	// columns returned by your statement. If the query returns no rows, then
-	// row.Scan() will return a sql.ErrNoRows error. We check for that and return 
+	// row.Scan() will return a sql.ErrNoRows error. We check for that and return
 	// our own models.ErrNoRecord error instead of a Snippet object.
-	err := row.Scan(&s.ID, &s.Title, &s.Content, &s.Created, &s.Expires)
+	err = row.Scan(&s.ID, &s.Title, &s.Content, &s.Created, &s.Expires)
 	if err == sql.ErrNoRows {
-	return nil, models.ErrNoRecord } else if err != nil {
-	return nil, err }
+		return nil, models.ErrNoRecord
+	} else if err != nil {
+		return nil, err
+	}
 	// If everything went OK then return the Snippet object.
 	return s, nil
 }
 
-// This will return the 10 most recently created snippets.
-func (m *SnippetModel) Latest() ([]*models.Snippet, error) { 
-	return nil, nil
+// This will return the 10 most recently created, non-expired snippets.
+func (m *SnippetModel) Latest(ctx context.Context) ([]*models.Snippet, error) {
+	stmt, err := m.stmt(stmtLatestSnippets, latestSnippetsQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, defaultListLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snippets := []*models.Snippet{}
+	for rows.Next() {
+		s := &models.Snippet{}
+		if err = rows.Scan(&s.ID, &s.Title, &s.Content, &s.Created, &s.Expires); err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snippets, nil
+}
+
+// List returns a page of snippets matching opts, ordered newest-first, along
+// with a PageInfo describing how to fetch the next page. Pagination uses a
+// keyset cursor over (created, id) rather than OFFSET, so results stay
+// stable even as new snippets are inserted ahead of the cursor.
+//
+// opts.Query is matched against the FULLTEXT index in MySQL's own natural
+// language mode: it ranks by relevance, drops stopwords, and ignores words
+// shorter than ft_min_word_len, so it does not guarantee substring
+// containment the way the postgres and sqlite backends' List do. See the
+// doc comment on models.ListOptions.Query.
+func (m *SnippetModel) List(ctx context.Context, opts models.ListOptions) ([]*models.Snippet, models.PageInfo, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	var where []string
+	var args []interface{}
+
+	if !opts.IncludeExpired {
+		where = append(where, "expires > UTC_TIMESTAMP()")
+	}
+
+	if opts.Query != "" {
+		// Backed by the FULLTEXT index added on (title, content), rather
+		// than a LIKE scan. Natural language mode ranks and filters
+		// matches instead of guaranteeing containment — see the doc
+		// comment above.
+		where = append(where, "MATCH(title, content) AGAINST (? IN NATURAL LANGUAGE MODE)")
+		args = append(args, opts.Query)
+	}
+
+	if opts.Cursor != "" {
+		created, id, err := models.DecodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, models.PageInfo{}, err
+		}
+		where = append(where, "(created, id) < (?, ?)")
+		args = append(args, created, id)
+	}
+
+	stmt := "SELECT id, title, content, created, expires FROM snippets"
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+	stmt += " ORDER BY created DESC, id DESC LIMIT ?"
+
+	// Ask for one extra row so we can tell whether a further page exists
+	// without a second round-trip.
+	args = append(args, limit+1)
+
+	rows, err := m.DB.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	snippets := []*models.Snippet{}
+	for rows.Next() {
+		s := &models.Snippet{}
+		if err = rows.Scan(&s.ID, &s.Title, &s.Content, &s.Created, &s.Expires); err != nil {
+			return nil, models.PageInfo{}, err
+		}
+		snippets = append(snippets, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	page := models.PageInfo{}
+	if len(snippets) > limit {
+		snippets = snippets[:limit]
+		last := snippets[len(snippets)-1]
+		page.NextCursor = models.EncodeCursor(last.Created, last.ID)
+		page.HasMore = true
+	}
+
+	return snippets, page, nil
+}
+
+// Update modifies the title and content of an existing snippet.
+func (m *SnippetModel) Update(ctx context.Context, id int, title, content string) error {
+	stmt := `UPDATE snippets SET title = ?, content = ? WHERE id = ?`
+	_, err := m.DB.ExecContext(ctx, stmt, title, content, id)
+	return err
+}
+
+// Delete removes a snippet from the database.
+func (m *SnippetModel) Delete(ctx context.Context, id int) error {
+	stmt := `DELETE FROM snippets WHERE id = ?`
+	_, err := m.DB.ExecContext(ctx, stmt, id)
+	return err
 }
\ No newline at end of file
@@ -0,0 +1,66 @@
+package mysql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+// fakeDriver is a minimal database/sql driver, registered purely so this
+// benchmark can exercise SnippetModel.stmt without needing a live MySQL
+// server.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(name string) (driver.Conn, error) { return fakeConn{}, nil }
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, driver.ErrSkip }
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+func (fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return driver.ResultNoRows, nil
+}
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+func init() {
+	sql.Register("snippetbox_fake", fakeDriver{})
+}
+
+// BenchmarkStmtCache compares repeatedly fetching a cached prepared
+// statement against preparing one from scratch on every call, which is
+// what Insert/Get/Latest used to do before SnippetModel cached them.
+func BenchmarkStmtCache(b *testing.B) {
+	db, err := sql.Open("snippetbox_fake", "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	m := &SnippetModel{DB: db}
+
+	b.Run("cached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := m.stmt(stmtGetSnippet, getSnippetQuery); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stmt, err := db.Prepare(getSnippetQuery)
+			if err != nil {
+				b.Fatal(err)
+			}
+			stmt.Close()
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+
+	"davappler/snippetbox/pkg/models"
+)
+
+const insertTagQuery = `INSERT INTO tags (name) VALUES (?)
+			  ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)`
+
+const tagSnippetQuery = `INSERT IGNORE INTO snippet_tags (snippet_id, tag_id) VALUES (?, ?)`
+
+const listByTagQuery = `SELECT s.id, s.title, s.content, s.created, s.expires
+	FROM snippets s
+	JOIN snippet_tags st ON st.snippet_id = s.id
+	JOIN tags t ON t.id = st.tag_id
+	WHERE t.name = ? AND s.expires > UTC_TIMESTAMP()
+	ORDER BY s.created DESC`
+
+// SnippetTx runs Insert, plus the tag writes InsertTag and TagSnippet,
+// against an in-flight *sql.Tx so a caller can group several statements
+// into one atomic unit of work. It has no Get, Latest, List, Update or
+// Delete; those run only through SnippetModel, outside any transaction. It carries the context the transaction was opened
+// with, so every statement it runs inherits the same deadline/cancellation.
+type SnippetTx struct {
+	tx  *sql.Tx
+	ctx context.Context
+}
+
+// WithTx begins a transaction, passes a SnippetTx wrapping it to fn, and
+// commits once fn returns nil. If fn returns an error, or panics, the
+// transaction is rolled back instead.
+func (m *SnippetModel) WithTx(ctx context.Context, fn func(*SnippetTx) error) (err error) {
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		} else if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err = fn(&SnippetTx{tx: tx, ctx: ctx}); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Insert adds a new snippet within the transaction and returns its id.
+func (t *SnippetTx) Insert(title, content, expires string) (int, error) {
+	result, err := t.tx.ExecContext(t.ctx, insertSnippetQuery, title, content, expires)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// InsertTag creates the named tag if it doesn't already exist, and
+// returns its id either way.
+func (t *SnippetTx) InsertTag(name string) (int, error) {
+	result, err := t.tx.ExecContext(t.ctx, insertTagQuery, name)
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// TagSnippet associates snippetID with tagID, if they aren't already
+// associated.
+func (t *SnippetTx) TagSnippet(snippetID, tagID int) error {
+	_, err := t.tx.ExecContext(t.ctx, tagSnippetQuery, snippetID, tagID)
+	return err
+}
+
+// InsertWithTags inserts a snippet and associates it with tags, creating
+// any tags that don't already exist, all within a single transaction.
+func (m *SnippetModel) InsertWithTags(ctx context.Context, title, content, expires string, tags []string) (int, error) {
+	var id int
+
+	err := m.WithTx(ctx, func(t *SnippetTx) error {
+		var err error
+		id, err = t.Insert(title, content, expires)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range tags {
+			tagID, err := t.InsertTag(name)
+			if err != nil {
+				return err
+			}
+			if err := t.TagSnippet(id, tagID); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return id, err
+}
+
+// ListByTag returns the non-expired snippets associated with the named
+// tag, newest first.
+func (m *SnippetModel) ListByTag(ctx context.Context, name string) ([]*models.Snippet, error) {
+	rows, err := m.DB.QueryContext(ctx, listByTagQuery, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	snippets := []*models.Snippet{}
+	for rows.Next() {
+		s := &models.Snippet{}
+		if err = rows.Scan(&s.ID, &s.Title, &s.Content, &s.Created, &s.Expires); err != nil {
+			return nil, err
+		}
+		snippets = append(snippets, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return snippets, nil
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+)
+
+// fakeTxConn is a minimal database/sql driver connection that records every
+// statement it executes, so a test can tell whether a transaction's
+// statements were kept (Commit) or discarded (Rollback).
+type fakeTxConn struct {
+	executed []string
+}
+
+func (c *fakeTxConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeTxStmt{conn: c, query: query}, nil
+}
+func (c *fakeTxConn) Close() error { return nil }
+func (c *fakeTxConn) Begin() (driver.Tx, error) {
+	return &fakeTxTx{conn: c, startLen: len(c.executed)}, nil
+}
+
+type fakeTxStmt struct {
+	conn  *fakeTxConn
+	query string
+}
+
+func (s *fakeTxStmt) Close() error  { return nil }
+func (s *fakeTxStmt) NumInput() int { return -1 }
+func (s *fakeTxStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.conn.executed = append(s.conn.executed, s.query)
+	return fakeTxResult{id: int64(len(s.conn.executed))}, nil
+}
+func (s *fakeTxStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return nil, driver.ErrSkip
+}
+
+type fakeTxResult struct{ id int64 }
+
+func (r fakeTxResult) LastInsertId() (int64, error) { return r.id, nil }
+func (r fakeTxResult) RowsAffected() (int64, error) { return 1, nil }
+
+// fakeTxTx buffers nothing itself — statements land in conn.executed as
+// they run — but remembers how many had executed when the transaction
+// began, so Rollback can discard everything run since.
+type fakeTxTx struct {
+	conn     *fakeTxConn
+	startLen int
+}
+
+func (t *fakeTxTx) Commit() error { return nil }
+func (t *fakeTxTx) Rollback() error {
+	t.conn.executed = t.conn.executed[:t.startLen]
+	return nil
+}
+
+// lastFakeTxConn is set by fakeTxDriver.Open, so a test can inspect the
+// executed statements on the single connection it uses without depending
+// on how database/sql happens to pool connections.
+var lastFakeTxConn *fakeTxConn
+
+type fakeTxDriver struct{}
+
+func (fakeTxDriver) Open(name string) (driver.Conn, error) {
+	lastFakeTxConn = &fakeTxConn{}
+	return lastFakeTxConn, nil
+}
+
+func init() {
+	sql.Register("snippetbox_fake_tx", fakeTxDriver{})
+}
+
+func TestWithTxCommit(t *testing.T) {
+	db, err := sql.Open("snippetbox_fake_tx", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m := &SnippetModel{DB: db}
+
+	id, err := m.InsertWithTags(context.Background(), "title", "content", "7", []string{"gardening"})
+	if err != nil {
+		t.Fatalf("InsertWithTags returned an error: %v", err)
+	}
+	if id == 0 {
+		t.Errorf("want a non-zero id")
+	}
+}
+
+func TestWithTxRollbackOnError(t *testing.T) {
+	db, err := sql.Open("snippetbox_fake_tx", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	m := &SnippetModel{DB: db}
+
+	wantErr := errors.New("boom")
+	err = m.WithTx(context.Background(), func(tx *SnippetTx) error {
+		if _, err := tx.Insert("title", "content", "7"); err != nil {
+			t.Fatalf("tx.Insert returned an error: %v", err)
+		}
+		return wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("want %v; got %v", wantErr, err)
+	}
+
+	if lastFakeTxConn == nil {
+		t.Fatal("no connection was opened")
+	}
+	if len(lastFakeTxConn.executed) != 0 {
+		t.Errorf("want no statements to survive the rollback; got %v", lastFakeTxConn.executed)
+	}
+}
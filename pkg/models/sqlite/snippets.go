@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"davappler/snippetbox/pkg/models"
+)
+
+// defaultListLimit is the page size used by List when the caller doesn't
+// specify one, and the number of snippets returned by Latest.
+const defaultListLimit = 10
+
+// SnippetModel wraps a SQLite connection pool and implements
+// models.SnippetStore. It's primarily useful for tests and local
+// development where running a full MySQL server is overkill.
+type SnippetModel struct {
+	DB *sql.DB
+}
+
+// Insert adds a new snippet to the database and returns its id.
+func (m *SnippetModel) Insert(ctx context.Context, title, content, expires string) (int, error) {
+	days, err := strconv.Atoi(expires)
+	if err != nil {
+		return 0, fmt.Errorf("sqlite: invalid expires value %q: %w", expires, err)
+	}
+
+	created := time.Now().UTC()
+
+	stmt := `INSERT INTO snippets (title, content, created, expires)
+			  VALUES (?, ?, ?, ?)`
+
+	result, err := m.DB.ExecContext(ctx, stmt, title, content, created, created.AddDate(0, 0, days))
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(id), nil
+}
+
+// Get returns a specific, non-expired snippet based on its id.
+func (m *SnippetModel) Get(ctx context.Context, id int) (*models.Snippet, error) {
+	stmt := `SELECT id, title, content, created, expires FROM snippets
+		WHERE expires > ? AND id = ?`
+
+	row := m.DB.QueryRowContext(ctx, stmt, time.Now().UTC(), id)
+
+	s := &models.Snippet{}
+	err := row.Scan(&s.ID, &s.Title, &s.Content, &s.Created, &s.Expires)
+	if err == sql.ErrNoRows {
+		return nil, models.ErrNoRecord
+	} else if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Latest returns the 10 most recently created, non-expired snippets.
+func (m *SnippetModel) Latest(ctx context.Context) ([]*models.Snippet, error) {
+	snippets, _, err := m.List(ctx, models.ListOptions{Limit: defaultListLimit})
+	return snippets, err
+}
+
+// List returns a page of snippets matching opts, ordered newest-first,
+// using the same (created, id) keyset cursor as the other backends.
+func (m *SnippetModel) List(ctx context.Context, opts models.ListOptions) ([]*models.Snippet, models.PageInfo, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = defaultListLimit
+	}
+
+	var where []string
+	var args []interface{}
+
+	if !opts.IncludeExpired {
+		where = append(where, "expires > ?")
+		args = append(args, time.Now().UTC())
+	}
+
+	if opts.Query != "" {
+		where = append(where, "(title LIKE ? OR content LIKE ?)")
+		like := "%" + opts.Query + "%"
+		args = append(args, like, like)
+	}
+
+	if opts.Cursor != "" {
+		created, id, err := models.DecodeCursor(opts.Cursor)
+		if err != nil {
+			return nil, models.PageInfo{}, err
+		}
+		where = append(where, "(created, id) < (?, ?)")
+		args = append(args, created, id)
+	}
+
+	stmt := "SELECT id, title, content, created, expires FROM snippets"
+	if len(where) > 0 {
+		stmt += " WHERE " + strings.Join(where, " AND ")
+	}
+	stmt += " ORDER BY created DESC, id DESC LIMIT ?"
+
+	// Ask for one extra row so we can tell whether a further page exists
+	// without a second round-trip.
+	args = append(args, limit+1)
+
+	rows, err := m.DB.QueryContext(ctx, stmt, args...)
+	if err != nil {
+		return nil, models.PageInfo{}, err
+	}
+	defer rows.Close()
+
+	snippets := []*models.Snippet{}
+	for rows.Next() {
+		s := &models.Snippet{}
+		if err = rows.Scan(&s.ID, &s.Title, &s.Content, &s.Created, &s.Expires); err != nil {
+			return nil, models.PageInfo{}, err
+		}
+		snippets = append(snippets, s)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, models.PageInfo{}, err
+	}
+
+	page := models.PageInfo{}
+	if len(snippets) > limit {
+		snippets = snippets[:limit]
+		last := snippets[len(snippets)-1]
+		page.NextCursor = models.EncodeCursor(last.Created, last.ID)
+		page.HasMore = true
+	}
+
+	return snippets, page, nil
+}
+
+// Update modifies the title and content of an existing snippet.
+func (m *SnippetModel) Update(ctx context.Context, id int, title, content string) error {
+	stmt := `UPDATE snippets SET title = ?, content = ? WHERE id = ?`
+	_, err := m.DB.ExecContext(ctx, stmt, title, content, id)
+	return err
+}
+
+// Delete removes a snippet from the database.
+func (m *SnippetModel) Delete(ctx context.Context, id int) error {
+	stmt := `DELETE FROM snippets WHERE id = ?`
+	_, err := m.DB.ExecContext(ctx, stmt, id)
+	return err
+}
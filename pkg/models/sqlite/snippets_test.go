@@ -0,0 +1,99 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"davappler/snippetbox/pkg/models"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const createSnippetsTable = `
+CREATE TABLE snippets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	title VARCHAR(100) NOT NULL,
+	content TEXT NOT NULL,
+	created DATETIME NOT NULL,
+	expires DATETIME NOT NULL
+)`
+
+func newTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(createSnippetsTable); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// TestListPagination inserts a known number of snippets with distinct,
+// ordered created times, then pages through List using the cursor each
+// page returns, checking that every snippet is seen exactly once.
+func TestListPagination(t *testing.T) {
+	db := newTestDB(t)
+	m := &SnippetModel{DB: db}
+
+	const total = 25
+	base := time.Now().UTC()
+	expires := base.AddDate(0, 0, 7)
+
+	wantIDs := map[int]bool{}
+	for i := 0; i < total; i++ {
+		created := base.Add(time.Duration(i) * time.Second)
+		res, err := db.Exec(
+			`INSERT INTO snippets (title, content, created, expires) VALUES (?, ?, ?, ?)`,
+			"title", "content", created, expires,
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantIDs[int(id)] = true
+	}
+
+	seen := map[int]bool{}
+	opts := models.ListOptions{Limit: 7}
+
+	for pages := 0; ; pages++ {
+		if pages > total {
+			t.Fatalf("List did not terminate after %d pages", pages)
+		}
+
+		snippets, page, err := m.List(context.Background(), opts)
+		if err != nil {
+			t.Fatalf("List returned an error: %v", err)
+		}
+
+		for _, s := range snippets {
+			if seen[s.ID] {
+				t.Fatalf("snippet id %d was returned on more than one page", s.ID)
+			}
+			seen[s.ID] = true
+		}
+
+		if !page.HasMore {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+
+	if len(seen) != len(wantIDs) {
+		t.Fatalf("want %d snippets seen across all pages; got %d", len(wantIDs), len(seen))
+	}
+	for id := range wantIDs {
+		if !seen[id] {
+			t.Errorf("snippet id %d was never returned by any page", id)
+		}
+	}
+}